@@ -0,0 +1,167 @@
+// encoders.go
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// HWEncoder identifies a hardware-accelerated encoder family. Giving it its
+// own type instead of comparing raw format strings everywhere means
+// hwEncoderFamily is the one place that maps a format id to a family, and
+// buildHWFFmpegArgs switches over a closed set rather than string suffixes.
+type HWEncoder string
+
+const (
+	EncoderNVENC        HWEncoder = "nvenc"
+	EncoderVAAPI        HWEncoder = "vaapi"
+	EncoderQSV          HWEncoder = "qsv"
+	EncoderVideoToolbox HWEncoder = "videotoolbox"
+)
+
+// hwFormat describes one selectable hardware-accelerated output in the
+// formatList: its format id (as used by buildFFmpegArgs/defaultOutputPath),
+// the ffmpeg encoder name it probes for, and the title/description shown
+// in the TUI.
+type hwFormat struct {
+	id         string
+	ffmpegName string // name as reported by `ffmpeg -encoders`
+	title      string
+	desc       string
+}
+
+// hwFormatCatalog lists every hardware encoder we know how to drive. Only
+// the ones actually present on the host (per probeAvailableEncoders) are
+// added to the formatList at startup.
+var hwFormatCatalog = []hwFormat{
+	{id: "h264_nvenc", ffmpegName: "h264_nvenc", title: "H.264 (NVENC)", desc: "NVIDIA GPU-accelerated H.264"},
+	{id: "hevc_nvenc", ffmpegName: "hevc_nvenc", title: "HEVC (NVENC)", desc: "NVIDIA GPU-accelerated HEVC"},
+	{id: "h264_vaapi", ffmpegName: "h264_vaapi", title: "H.264 (VAAPI)", desc: "Intel/AMD VAAPI-accelerated H.264"},
+	{id: "hevc_vaapi", ffmpegName: "hevc_vaapi", title: "HEVC (VAAPI)", desc: "Intel/AMD VAAPI-accelerated HEVC"},
+	{id: "h264_qsv", ffmpegName: "h264_qsv", title: "H.264 (QuickSync)", desc: "Intel QuickSync-accelerated H.264"},
+	{id: "hevc_qsv", ffmpegName: "hevc_qsv", title: "HEVC (QuickSync)", desc: "Intel QuickSync-accelerated HEVC"},
+	{id: "h264_videotoolbox", ffmpegName: "h264_videotoolbox", title: "H.264 (VideoToolbox)", desc: "Apple VideoToolbox-accelerated H.264"},
+	{id: "hevc_videotoolbox", ffmpegName: "hevc_videotoolbox", title: "HEVC (VideoToolbox)", desc: "Apple VideoToolbox-accelerated HEVC"},
+}
+
+// vaapiRenderDevice is the default VAAPI render node. Most Linux desktops
+// only have one GPU, so this is a reasonable default rather than something
+// worth threading through as a flag yet.
+const vaapiRenderDevice = "/dev/dri/renderD128"
+
+// probeAvailableEncoders runs `ffmpeg -hide_banner -encoders` once and
+// returns the set of encoder names ffmpeg reports as built in. Missing
+// ffmpeg or a parse failure just yields an empty set, since hardware
+// encoder support is always optional.
+func probeAvailableEncoders() map[string]bool {
+	available := make(map[string]bool)
+
+	out, err := exec.Command(activeFFPaths.FFmpeg, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return available
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		// encoder lines look like: " V..... h264_nvenc   NVIDIA NVENC H.264 encoder"
+		if len(fields) < 2 {
+			continue
+		}
+		available[fields[1]] = true
+	}
+	return available
+}
+
+// availableHWFormats filters hwFormatCatalog down to the encoders ffmpeg
+// actually reports on this host.
+func availableHWFormats(available map[string]bool) []hwFormat {
+	var out []hwFormat
+	for _, f := range hwFormatCatalog {
+		if available[f.ffmpegName] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// hwEncoderFamily maps a format id to the HWEncoder family driving it, or
+// ("", false) if format isn't a hardware encoder.
+func hwEncoderFamily(format string) (HWEncoder, bool) {
+	switch {
+	case strings.HasSuffix(format, "_nvenc"):
+		return EncoderNVENC, true
+	case strings.HasSuffix(format, "_vaapi"):
+		return EncoderVAAPI, true
+	case strings.HasSuffix(format, "_qsv"):
+		return EncoderQSV, true
+	case strings.HasSuffix(format, "_videotoolbox"):
+		return EncoderVideoToolbox, true
+	default:
+		return "", false
+	}
+}
+
+// buildHWFFmpegArgs builds the ffmpeg argument list for a hardware-accelerated
+// format id (e.g. "h264_nvenc", "hevc_vaapi"). The bitrate/CRF-equivalent
+// defaults differ per hardware family since they don't share a rate-control
+// model with libx264/libx265.
+func buildHWFFmpegArgs(inputPath, outputPath, format string) []string {
+	family, _ := hwEncoderFamily(format)
+	codec := strings.TrimSuffix(format, "_"+string(family))
+
+	switch family {
+	case EncoderNVENC:
+		return []string{
+			"-y",
+			"-i", inputPath,
+			"-c:v", format,
+			"-preset", "p5",
+			"-rc", "vbr",
+			"-cq", "23",
+			"-c:a", "aac",
+			"-b:a", "192k",
+			outputPath,
+		}
+	case EncoderVAAPI:
+		return []string{
+			"-y",
+			"-vaapi_device", vaapiRenderDevice,
+			"-i", inputPath,
+			"-vf", "format=nv12,hwupload",
+			"-c:v", format,
+			"-qp", "23",
+			"-c:a", "aac",
+			"-b:a", "192k",
+			outputPath,
+		}
+	case EncoderQSV:
+		return []string{
+			"-y",
+			"-init_hw_device", "qsv=hw",
+			"-filter_hw_device", "hw",
+			"-i", inputPath,
+			"-c:v", format,
+			"-global_quality", "23",
+			"-c:a", "aac",
+			"-b:a", "192k",
+			outputPath,
+		}
+	case EncoderVideoToolbox:
+		// VideoToolbox has no CRF-equivalent; a sane default bitrate per codec.
+		bitrate := "8M"
+		if codec == "hevc" {
+			bitrate = "6M"
+		}
+		return []string{
+			"-y",
+			"-i", inputPath,
+			"-c:v", format,
+			"-b:v", bitrate,
+			"-c:a", "aac",
+			"-b:a", "192k",
+			outputPath,
+		}
+	default:
+		return buildFFmpegArgs(inputPath, outputPath, "h264", StreamSelection{})
+	}
+}
@@ -0,0 +1,134 @@
+// ffpaths.go
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// activeFFPaths is resolved once in main (see ResolveFFPaths) and read by
+// Convert/startConversionCmd/probeDuration/probeAvailableEncoders instead of
+// the bare "ffmpeg"/"ffprobe" strings, so non-standard installs (macOS
+// Homebrew, Windows, custom builds) can be pointed at directly.
+var activeFFPaths = DefaultFFPaths()
+
+// FFPaths holds the resolved ffmpeg/ffprobe executables to invoke.
+type FFPaths struct {
+	FFmpeg  string `json:"ffmpeg"`
+	FFprobe string `json:"ffprobe"`
+}
+
+// DefaultFFPaths looks ffmpeg/ffprobe up on $PATH, falling back to the bare
+// command names if that fails (exec.Command will then surface the real
+// "not found" error at run time).
+func DefaultFFPaths() FFPaths {
+	paths := FFPaths{FFmpeg: "ffmpeg", FFprobe: "ffprobe"}
+	if p, err := exec.LookPath("ffmpeg"); err == nil {
+		paths.FFmpeg = p
+	}
+	if p, err := exec.LookPath("ffprobe"); err == nil {
+		paths.FFprobe = p
+	}
+	return paths
+}
+
+// withEnvOverrides applies DAVICONVERTER_FFMPEG/DAVICONVERTER_FFPROBE on top
+// of paths, if set.
+func (p FFPaths) withEnvOverrides() FFPaths {
+	if v := os.Getenv("DAVICONVERTER_FFMPEG"); v != "" {
+		p.FFmpeg = v
+	}
+	if v := os.Getenv("DAVICONVERTER_FFPROBE"); v != "" {
+		p.FFprobe = v
+	}
+	return p
+}
+
+// ffPathsConfigPath is $XDG_CONFIG_HOME/daviconverter/ffpaths.json, the
+// persisted counterpart to presets.json.
+func ffPathsConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "daviconverter", "ffpaths.json")
+}
+
+// loadFFPathsConfig reads a previously-persisted FFPaths choice. A missing
+// file isn't an error -- there's just nothing saved yet.
+func loadFFPathsConfig(path string) (FFPaths, error) {
+	if path == "" {
+		return FFPaths{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FFPaths{}, nil
+		}
+		return FFPaths{}, err
+	}
+	var p FFPaths
+	if err := json.Unmarshal(data, &p); err != nil {
+		return FFPaths{}, err
+	}
+	return p, nil
+}
+
+// saveFFPathsConfig persists the resolved paths so the next run remembers
+// them without needing the flags/env vars again.
+func saveFFPathsConfig(path string, p FFPaths) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ResolveFFPaths layers the ffmpeg/ffprobe path sources, lowest to highest
+// precedence: $PATH defaults, the persisted config file, environment
+// variables, then the --ffmpeg/--ffprobe flags. The result is persisted
+// back to config so the next run starts from it.
+func ResolveFFPaths(ffmpegFlag, ffprobeFlag string) FFPaths {
+	paths := DefaultFFPaths()
+
+	if saved, err := loadFFPathsConfig(ffPathsConfigPath()); err == nil {
+		if saved.FFmpeg != "" {
+			paths.FFmpeg = saved.FFmpeg
+		}
+		if saved.FFprobe != "" {
+			paths.FFprobe = saved.FFprobe
+		}
+	}
+
+	paths = paths.withEnvOverrides()
+
+	if ffmpegFlag != "" {
+		paths.FFmpeg = ffmpegFlag
+	}
+	if ffprobeFlag != "" {
+		paths.FFprobe = ffprobeFlag
+	}
+
+	_ = saveFFPathsConfig(ffPathsConfigPath(), paths)
+	return paths
+}
+
+// versionLine runs `<path> -version` and returns just its first line (e.g.
+// "ffmpeg version 6.1.1 Copyright (c) 2000-2023 the FFmpeg developers").
+func versionLine(path string) string {
+	out, err := exec.Command(path, "-version").Output()
+	if err != nil {
+		return "unavailable: " + err.Error()
+	}
+	first, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,168 @@
+// hls.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Rung is one rendition in an adaptive-bitrate ladder.
+type Rung struct {
+	Height       int    `json:"height"`
+	VideoBitrate string `json:"video_bitrate"`
+	AudioBitrate string `json:"audio_bitrate"`
+}
+
+// ladderCandidates are the rungs offered on screenLadder; the top three are
+// also the preset default (see defaultLadder).
+var ladderCandidates = []Rung{
+	{Height: 1080, VideoBitrate: "5000k", AudioBitrate: "128k"},
+	{Height: 720, VideoBitrate: "2800k", AudioBitrate: "128k"},
+	{Height: 480, VideoBitrate: "1400k", AudioBitrate: "96k"},
+	{Height: 360, VideoBitrate: "800k", AudioBitrate: "96k"},
+}
+
+// segmentSecondsOptions are the choices screenLadder cycles through.
+var segmentSecondsOptions = []int{4, 6, 10}
+
+const defaultSegmentSeconds = 6
+
+func defaultLadder() []Rung {
+	ladder := make([]Rung, 3)
+	copy(ladder, ladderCandidates[:3])
+	return ladder
+}
+
+func nextSegmentSeconds(cur int) int {
+	for i, v := range segmentSecondsOptions {
+		if v == cur && i+1 < len(segmentSecondsOptions) {
+			return segmentSecondsOptions[i+1]
+		}
+	}
+	return segmentSecondsOptions[0]
+}
+
+func prevSegmentSeconds(cur int) int {
+	for i, v := range segmentSecondsOptions {
+		if v == cur && i > 0 {
+			return segmentSecondsOptions[i-1]
+		}
+	}
+	return segmentSecondsOptions[len(segmentSecondsOptions)-1]
+}
+
+// applyLadderChoice stamps the ladder/segment duration the user picked on
+// screenLadder onto the matching preset, so buildFFmpegArgs (which only
+// takes a preset ID) sees it on the next lookup.
+func applyLadderChoice(presetID string, ladder []Rung, segmentSeconds int) {
+	for i := range activePresets {
+		if activePresets[i].ID == presetID {
+			activePresets[i].Ladder = ladder
+			activePresets[i].SegmentSeconds = segmentSeconds
+			return
+		}
+	}
+}
+
+// ladderFilterComplex builds the split+scale filtergraph shared by HLS and
+// DASH: one [v0out]..[vNout] label per rung, scaled to its target height.
+func ladderFilterComplex(ladder []Rung) string {
+	labels := make([]string, len(ladder))
+	for i := range ladder {
+		labels[i] = fmt.Sprintf("[v%d]", i)
+	}
+	parts := []string{fmt.Sprintf("[0:v]split=%d%s", len(ladder), strings.Join(labels, ""))}
+	for i, r := range ladder {
+		parts = append(parts, fmt.Sprintf("[v%d]scale=-2:%d[v%dout]", i, r.Height, i))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// buildHLSArgs builds an ffmpeg invocation that encodes preset.Ladder as
+// separate HLS variant streams plus a master playlist, all in one pass.
+func buildHLSArgs(preset Preset, inputPath, outputDir string) []string {
+	ensureOutputDir(outputDir)
+
+	ladder := preset.Ladder
+	if len(ladder) == 0 {
+		ladder = defaultLadder()
+	}
+	seg := preset.SegmentSeconds
+	if seg <= 0 {
+		seg = defaultSegmentSeconds
+	}
+
+	args := []string{"-y", "-i", inputPath, "-filter_complex", ladderFilterComplex(ladder)}
+
+	streamMap := make([]string, len(ladder))
+	for i, r := range ladder {
+		args = append(args,
+			"-map", fmt.Sprintf("[v%dout]", i),
+			fmt.Sprintf("-c:v:%d", i), "libx264",
+			fmt.Sprintf("-b:v:%d", i), r.VideoBitrate,
+			"-map", "a:0",
+			fmt.Sprintf("-c:a:%d", i), "aac",
+			fmt.Sprintf("-b:a:%d", i), r.AudioBitrate,
+		)
+		streamMap[i] = fmt.Sprintf("v:%d,a:%d", i, i)
+	}
+
+	args = append(args,
+		"-var_stream_map", strings.Join(streamMap, " "),
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(seg),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outputDir, "v%v", "seg_%03d.ts"),
+		"-master_pl_name", "index.m3u8",
+		filepath.Join(outputDir, "v%v", "prog_index.m3u8"),
+	)
+	return args
+}
+
+// buildDASHArgs builds an ffmpeg invocation that encodes preset.Ladder into
+// a single DASH manifest (manifest.mpd): every rung goes into one video
+// adaptation set, and the shared audio stream into a second one.
+func buildDASHArgs(preset Preset, inputPath, outputDir string) []string {
+	ensureOutputDir(outputDir)
+
+	ladder := preset.Ladder
+	if len(ladder) == 0 {
+		ladder = defaultLadder()
+	}
+	seg := preset.SegmentSeconds
+	if seg <= 0 {
+		seg = defaultSegmentSeconds
+	}
+
+	args := []string{"-y", "-i", inputPath, "-filter_complex", ladderFilterComplex(ladder)}
+
+	videoStreams := make([]string, len(ladder))
+	for i, r := range ladder {
+		args = append(args,
+			"-map", fmt.Sprintf("[v%dout]", i),
+			fmt.Sprintf("-c:v:%d", i), "libx264",
+			fmt.Sprintf("-b:v:%d", i), r.VideoBitrate,
+		)
+		videoStreams[i] = strconv.Itoa(i)
+	}
+
+	args = append(args,
+		"-map", "a:0",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-f", "dash",
+		"-seg_duration", strconv.Itoa(seg),
+		"-adaptation_sets", fmt.Sprintf("id=0,streams=%s id=1,streams=a", strings.Join(videoStreams, ",")),
+		filepath.Join(outputDir, "manifest.mpd"),
+	)
+	return args
+}
+
+// ensureOutputDir creates the renditions directory for HLS/DASH outputs;
+// ffmpeg won't create it for us the way it does a single output file.
+func ensureOutputDir(dir string) {
+	_ = os.MkdirAll(dir, 0o755)
+}
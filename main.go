@@ -9,11 +9,22 @@ import (
 )
 
 func main() {
-	if _, err := exec.LookPath("ffmpeg"); err != nil {
-		fmt.Println("ffmpeg not found in path, Intall it and try again")
+	ffmpegFlag := flag.String("ffmpeg", "", "path to the ffmpeg binary (overrides config/env/$PATH)")
+	ffprobeFlag := flag.String("ffprobe", "", "path to the ffprobe binary (overrides config/env/$PATH)")
+	outPathFlag := flag.String("out", "", "output file path (optional)")
+	parallelFlag := flag.Int("parallel", defaultParallelism, "how many batch conversions to run at once")
+	flag.Parse()
+
+	activeFFPaths = ResolveFFPaths(*ffmpegFlag, *ffprobeFlag)
+	if *parallelFlag > 0 {
+		configuredParallelism = *parallelFlag
+	}
+
+	if _, err := exec.LookPath(activeFFPaths.FFmpeg); err != nil {
+		fmt.Println("ffmpeg not found at", activeFFPaths.FFmpeg, "- install it or pass --ffmpeg")
 		os.Exit(1)
 	}
-	// flag.Parse()
+
 	final, err := RunTUI()
 	if err != nil {
 		fmt.Println("TUI error:", err)
@@ -23,22 +34,19 @@ func main() {
 		fmt.Println("Canceled by user; exiting.")
 		os.Exit(0)
 	}
-	// format, err := formatValidator(formatFlag)
-	// if err != nil {
-	// 	os.Exit(1)
-	// }
+	if final.handledInTUI {
+		// the TUI already ran (or batched) the conversion(s) itself
+		fmt.Println("conversion finished successfully ")
+		return
+	}
+
 	format := final.format
 	inputPath := final.value
 
-	// inPathFlag := flag.String("in", "", "input video path")
-	outPathFlag := flag.String("out", "", "output file path (optional)")
-	// formatFlag := flag.String("format", "h264", "output format: h264|prores|dnxhd|wav")
-
 	if err := isInputEmpty(final.value); err != nil {
 		os.Exit(1)
 	}
 
-	// inputPath := *inPathFlag
 	var outputPath string
 	if *outPathFlag == "" {
 		outputPath = defaultOutputPath(final.value, format)
@@ -52,18 +60,3 @@ func main() {
 	}
 	fmt.Println("conversion finished successfully ")
 }
-
-// func main() {
-// 	final, err := RunTUI()
-// 	if err != nil {
-// 		fmt.Println("TUI error:", err)
-// 		os.Exit(1)
-// 	}
-// 	if final.canceled {
-// 		fmt.Println("Canceled by user; exiting.")
-// 		os.Exit(0)
-// 	}
-//
-// 	fmt.Println("Input path:", final.value)
-// 	fmt.Println("Selected format:", final.format)
-// }
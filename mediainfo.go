@@ -0,0 +1,161 @@
+// mediainfo.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// Format mirrors the "format" object in ffprobe's JSON output.
+type Format struct {
+	Filename   string `json:"filename"`
+	FormatName string `json:"format_name"`
+	Duration   string `json:"duration"`
+	Size       string `json:"size"`
+	BitRate    string `json:"bit_rate"`
+}
+
+// VideoStream is one video stream as reported by ffprobe -show_streams.
+type VideoStream struct {
+	Index     int // position among video streams (0-based; the N in -map 0:v:N)
+	CodecName string
+	Width     int
+	Height    int
+	FrameRate string
+	Language  string
+}
+
+// AudioStream is one audio stream as reported by ffprobe -show_streams.
+type AudioStream struct {
+	Index         int // position among audio streams (0-based; the M in -map 0:a:M)
+	CodecName     string
+	SampleRate    string
+	Channels      int
+	ChannelLayout string
+	Language      string
+}
+
+// SubtitleStream is one subtitle stream as reported by ffprobe -show_streams.
+type SubtitleStream struct {
+	Index     int // position among subtitle streams (0-based; the S in -map 0:s:S)
+	CodecName string
+	Language  string
+}
+
+// MediaInfo is the structured result of probing a file: the container-level
+// Format plus its streams split out by type. Splitting by codec_type up
+// front is what lets screenStreams index straight into Video/Audio/Subtitle
+// instead of re-filtering ffprobe's flat stream list on every render.
+type MediaInfo struct {
+	Format   Format
+	Duration float64
+
+	Video    []VideoStream
+	Audio    []AudioStream
+	Subtitle []SubtitleStream
+}
+
+// rawProbeOutput and rawStream mirror ffprobe's JSON closely enough to
+// unmarshal into; Probe then splits rawStream entries out into the typed
+// Video/Audio/Subtitle slices by codec_type.
+type rawProbeOutput struct {
+	Format  Format      `json:"format"`
+	Streams []rawStream `json:"streams"`
+}
+
+type rawStream struct {
+	CodecType     string            `json:"codec_type"`
+	CodecName     string            `json:"codec_name"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	RFrameRate    string            `json:"r_frame_rate"`
+	SampleRate    string            `json:"sample_rate"`
+	Channels      int               `json:"channels"`
+	ChannelLayout string            `json:"channel_layout"`
+	Tags          map[string]string `json:"tags"`
+}
+
+// Probe runs ffprobe -show_format -show_streams and unmarshals its JSON
+// output into a MediaInfo. It replaces the old duration-only probe with
+// everything buildFFmpegArgs' stream-mapping needs.
+func Probe(path string) (*MediaInfo, error) {
+	out, err := exec.Command(
+		activeFFPaths.FFprobe,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	var raw rawProbeOutput
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	info := &MediaInfo{Format: raw.Format}
+	if d, err := strconv.ParseFloat(raw.Format.Duration, 64); err == nil {
+		info.Duration = d
+	}
+
+	for _, s := range raw.Streams {
+		lang := s.Tags["language"]
+		switch s.CodecType {
+		case "video":
+			info.Video = append(info.Video, VideoStream{
+				Index: len(info.Video), CodecName: s.CodecName,
+				Width: s.Width, Height: s.Height, FrameRate: s.RFrameRate, Language: lang,
+			})
+		case "audio":
+			info.Audio = append(info.Audio, AudioStream{
+				Index: len(info.Audio), CodecName: s.CodecName,
+				SampleRate: s.SampleRate, Channels: s.Channels, ChannelLayout: s.ChannelLayout, Language: lang,
+			})
+		case "subtitle":
+			info.Subtitle = append(info.Subtitle, SubtitleStream{
+				Index: len(info.Subtitle), CodecName: s.CodecName, Language: lang,
+			})
+		}
+	}
+	return info, nil
+}
+
+// StreamSelection is which video/audio/subtitle streams (by position within
+// MediaInfo.Video/Audio/Subtitle, not ffprobe's absolute stream index) to
+// keep, plus an optional audio channel downmix and sample-rate conversion.
+// A zero-value StreamSelection means "don't inject any -map/-ac/-ar args" --
+// ffmpeg's own default mapping.
+type StreamSelection struct {
+	VideoStreams    []int
+	AudioStreams    []int
+	SubtitleStreams []int
+	DownmixChannels string
+	SampleRateHz    string
+}
+
+// buildStreamArgs turns a StreamSelection into the -map/-ac/-ar args
+// buildArgsForPreset injects right after -i.
+func buildStreamArgs(sel StreamSelection) []string {
+	var args []string
+	for _, n := range sel.VideoStreams {
+		args = append(args, "-map", fmt.Sprintf("0:v:%d", n))
+	}
+	for _, n := range sel.AudioStreams {
+		args = append(args, "-map", fmt.Sprintf("0:a:%d", n))
+	}
+	for _, n := range sel.SubtitleStreams {
+		args = append(args, "-map", fmt.Sprintf("0:s:%d", n))
+	}
+	if sel.DownmixChannels != "" {
+		args = append(args, "-ac", sel.DownmixChannels)
+	}
+	if sel.SampleRateHz != "" {
+		args = append(args, "-ar", sel.SampleRateHz)
+	}
+	return args
+}
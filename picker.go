@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -20,12 +21,34 @@ type screen int
 const (
 	screenPicker screen = iota
 	screenFormat
+	screenStreams
+	screenLadder
 	screenConfirm
 	screenRunning
+	screenQueue
+	screenSettings
 	screenDone
 	screenError
 )
 
+// downmixOptions are the choices screenStreams cycles the "channels" row
+// through; "" means leave -ac unset (ffmpeg keeps the source's own layout).
+var downmixOptions = []string{"", "2", "1"}
+
+// sampleRateOptions are the choices screenStreams cycles the "sample rate"
+// row through; "" means leave -ar unset (ffmpeg keeps the source's rate).
+var sampleRateOptions = []string{"", "48000", "44100", "32000", "22050"}
+
+// defaultParallelism is how many jobs the queue runs at once when the user
+// hasn't configured anything else.
+const defaultParallelism = 2
+
+// configuredParallelism is how many jobs the queue runs at once. It's set
+// once in main from the --parallel flag (see main.go), the same
+// flag-overrides-a-package-var pattern as activeFFPaths; initialModel reads
+// it when building the batch queue.
+var configuredParallelism = defaultParallelism
+
 const (
 	padding  = 2
 	maxWidth = 80
@@ -57,22 +80,76 @@ type model struct {
 	percent    float64
 	lastStatus string
 
-	cancelFn context.CancelFunc
-	cmd      *exec.Cmd
+	// selectedInputs accumulates files picked while batching (see screenPicker);
+	// a single selection still flows through value/output/screenConfirm as before.
+	selectedInputs []string
+
+	// queue holds the batch jobs once the user leaves the format screen with
+	// more than one file queued up. Single-file runs don't use it; they run
+	// as job ID 0 in the same cancelFns/cmds/jobChans maps, so cancellation
+	// and progress routing share one code path.
+	queue       *Queue
+	parallelism int
+	queueCursor int
+
+	// screenLadder state: which of ladderCandidates are enabled and the
+	// chosen segment duration for an HLS/DASH run.
+	ladderCursor   int
+	ladderEnabled  []bool
+	segmentSeconds int
+
+	cancelFns map[int]context.CancelFunc
+	cmds      map[int]*exec.Cmd
+	jobChans  map[int]chan tea.Msg
+
+	// handledInTUI marks that the selected conversion(s) already ran to
+	// completion inside the TUI, so main() shouldn't start another one.
+	handledInTUI bool
+
+	// screenSettings state, computed once when the screen is entered.
+	ffmpegVersion  string
+	ffprobeVersion string
+
+	// previewText holds the rendered thumbnail preview shown on screenConfirm,
+	// if previewMode() enables one. Empty means no preview (feature off, or
+	// still loading).
+	previewText string
+
+	// screenStreams state: the probed MediaInfo for the current input, which
+	// of its video/audio/subtitle streams are kept, the chosen downmix, and
+	// the resulting StreamSelection passed to startConversionCmd. Only the
+	// single-file flow visits this screen; batch jobs use a zero-value
+	// StreamSelection, same as they skip screenLadder.
+	mediaInfo       *MediaInfo
+	videoKeep       []bool
+	audioKeep       []bool
+	subKeep         []bool
+	streamCursor    int
+	downmixIdx      int
+	sampleRateIdx   int
+	streamSelection StreamSelection
 
 	err      error
 	canceled bool
-
-	progressChan chan tea.Msg
 }
 
 type (
-	progressMsg     float64
-	ffmpegStatusMsg string
-	ffmpegErrMsg    error
+	progressMsg struct {
+		jobID   int
+		percent float64
+	}
+	ffmpegStatusMsg struct {
+		jobID  int
+		status string
+	}
+	ffmpegErrMsg struct {
+		jobID int
+		err   error
+	}
 )
 
 type startedMsg struct {
+	jobID  int
 	cancel context.CancelFunc
 	cmd    *exec.Cmd
 	ch     chan tea.Msg
@@ -86,6 +163,40 @@ func clearErrorAfter(t time.Duration) tea.Cmd {
 	})
 }
 
+// previewMsg carries the rendered confirm-screen thumbnail preview, or err
+// if generating it failed. text is empty with a nil err when previewMode()
+// has the feature switched off.
+type previewMsg struct {
+	text string
+	err  error
+}
+
+// generatePreviewCmd renders a preview of path in the background so entering
+// screenConfirm doesn't block the TUI on an ffmpeg invocation. preset decides
+// the preview shape: a "sheet" preset previews the same stitched contact
+// sheet it's about to produce, everything else previews a single frame.
+func generatePreviewCmd(path string, preset Preset) tea.Cmd {
+	return func() tea.Msg {
+		text, err := RenderPreview(path, preset)
+		return previewMsg{text: text, err: err}
+	}
+}
+
+// mediaInfoMsg carries the probed stream layout for screenStreams.
+type mediaInfoMsg struct {
+	info *MediaInfo
+	err  error
+}
+
+// probeMediaCmd runs Probe in the background so entering screenStreams
+// doesn't block the TUI on an ffprobe invocation.
+func probeMediaCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := Probe(path)
+		return mediaInfoMsg{info: info, err: err}
+	}
+}
+
 func initialModel() model {
 	// filepath input
 	fp := filepicker.New()
@@ -100,12 +211,11 @@ func initialModel() model {
 	fp.ShowHidden = false
 	fp.AutoHeight = true
 
-	// format input
-	items := []list.Item{
-		formatItem{title: "H.264 (MP4)", desc: "Smaller files, generally supported", id: "h264"},
-		formatItem{title: "Apple ProRes (MOV)", desc: "Edit-friendly, large files (ProRes)", id: "prores"},
-		formatItem{title: "DNxHD / DNxHR (MXF)", desc: "Avid-style mezzanine codec", id: "dnxhd"},
-		formatItem{title: "WAV 48kHz (Audio only)", desc: "Export audio only as WAV", id: "wav"},
+	// format input: built-ins + available hardware encoders + user presets
+	activePresets = LoadPresets(probeAvailableEncoders())
+	items := make([]list.Item, 0, len(activePresets))
+	for _, p := range activePresets {
+		items = append(items, formatItem{title: p.Title, desc: p.Description, id: p.ID})
 	}
 
 	delegate := list.NewDefaultDelegate()
@@ -117,14 +227,18 @@ func initialModel() model {
 	pb.SetPercent(0)
 
 	return model{
-		screen:     screenPicker,
-		filepicker: fp,
-		formatList: ls,
-		progress:   pb,
-		percent:    0,
-		format:     "h264",
-		err:        nil,
-		canceled:   false,
+		screen:      screenPicker,
+		filepicker:  fp,
+		formatList:  ls,
+		progress:    pb,
+		percent:     0,
+		format:      "h264",
+		parallelism: configuredParallelism,
+		cancelFns:   make(map[int]context.CancelFunc),
+		cmds:        make(map[int]*exec.Cmd),
+		jobChans:    make(map[int]chan tea.Msg),
+		err:         nil,
+		canceled:    false,
 	}
 }
 
@@ -141,6 +255,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "ctrl+c", "q":
 				m.canceled = true
 				return m, tea.Quit
+			case "f":
+				// finish batching and move on with whatever's queued up
+				if len(m.selectedInputs) > 0 {
+					m.screen = screenFormat
+				}
+				return m, nil
+			case "d":
+				// enqueue every allowed file in the current directory, skipping
+				// any already queued so repeated presses can't double-enqueue
+				// the same input into two racing jobs.
+				added := scanDirForInputs(m.filepicker.CurrentDirectory, m.filepicker.AllowedTypes)
+				queued := make(map[string]bool, len(m.selectedInputs))
+				for _, in := range m.selectedInputs {
+					queued[in] = true
+				}
+				for _, in := range added {
+					if !queued[in] {
+						m.selectedInputs = append(m.selectedInputs, in)
+						queued[in] = true
+					}
+				}
+				return m, nil
+			case "s":
+				m.ffmpegVersion = versionLine(activeFFPaths.FFmpeg)
+				m.ffprobeVersion = versionLine(activeFFPaths.FFprobe)
+				m.screen = screenSettings
+				return m, nil
 			}
 		case clearErrorMsg:
 			m.err = nil
@@ -149,11 +290,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.filepicker, cmd = m.filepicker.Update(msg)
 
-		// Did the user select a file?
+		// Did the user select a file? Add it to the batch and keep
+		// browsing; pressing "f" moves on to format selection.
 		if didSelect, path := m.filepicker.DidSelectFile(msg); didSelect {
-			// Get the path of the selected file.
 			m.value = path
-			m.screen = screenFormat
+			m.selectedInputs = append(m.selectedInputs, path)
 		}
 		// Did the user select a disabled file?
 		// This is only necessary to display an error to the user.
@@ -179,9 +320,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.format = fi.id
 					}
 				}
-				m.output = defaultOutputPath(m.value, m.format)
-				m.screen = screenConfirm
-				return m, nil
+
+				if len(m.selectedInputs) > 1 {
+					// batch mode: enqueue every picked file and start
+					// chewing through them up to m.parallelism at a time.
+					m.queue = NewQueue(m.parallelism)
+					for _, in := range m.selectedInputs {
+						m.queue.Enqueue(in, m.format)
+					}
+					m.screen = screenQueue
+					return m, m.startNextJobs()
+				}
+
+				if !presetUsesStreamSelection(PresetByID(activePresets, m.format)) {
+					// this preset family (hw encoders, hls/dash, sheet) never
+					// threads a stream selection into its ffmpeg invocation,
+					// so screenStreams would just be picking options that
+					// get silently discarded -- skip straight past it.
+					m.streamSelection = StreamSelection{}
+					return m, m.advanceFromStreams()
+				}
+
+				// single file: let the user pick which streams to keep
+				// before moving on to the ladder/confirm screen.
+				m.mediaInfo = nil
+				m.streamCursor = 0
+				m.screen = screenStreams
+				return m, probeMediaCmd(m.value)
 
 			case tea.KeyEsc:
 				m.screen = screenPicker
@@ -196,6 +361,127 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.formatList, cmd = m.formatList.Update(msg)
 		return m, cmd
 
+	case screenStreams:
+		switch msg := msg.(type) {
+		case mediaInfoMsg:
+			if msg.err != nil || msg.info == nil {
+				// probing failed; just fall through with ffmpeg's default
+				// mapping instead of blocking the user on a broken screen.
+				m.streamSelection = StreamSelection{}
+				return m, m.advanceFromStreams()
+			}
+			m.mediaInfo = msg.info
+			m.videoKeep = make([]bool, len(msg.info.Video))
+			m.audioKeep = make([]bool, len(msg.info.Audio))
+			m.subKeep = make([]bool, len(msg.info.Subtitle))
+			// video defaults to kept only when the target preset actually
+			// encodes video -- an audio-only preset like "wav" combines
+			// -vn with any video -map into an invocation ffmpeg rejects.
+			keepVideo := PresetByID(activePresets, m.format).VideoCodec != ""
+			for i := range m.videoKeep {
+				m.videoKeep[i] = keepVideo
+			}
+			for i := range m.audioKeep {
+				m.audioKeep[i] = true
+			}
+			// subtitles default to unselected -- most conversions don't want them.
+			m.streamCursor = 0
+			m.downmixIdx = 0
+			m.sampleRateIdx = 0
+			return m, nil
+
+		case tea.KeyMsg:
+			rows := m.streamRows()
+			switch msg.String() {
+			case "up", "k":
+				if m.streamCursor > 0 {
+					m.streamCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.streamCursor < len(rows)-1 {
+					m.streamCursor++
+				}
+				return m, nil
+			case " ", "x":
+				if m.streamCursor < len(rows) {
+					m.toggleStreamRow(rows[m.streamCursor])
+				}
+				return m, nil
+			case "[":
+				if m.streamCursor < len(rows) {
+					m.cycleStreamRow(rows[m.streamCursor], -1)
+				}
+				return m, nil
+			case "]":
+				if m.streamCursor < len(rows) {
+					m.cycleStreamRow(rows[m.streamCursor], 1)
+				}
+				return m, nil
+			case "enter":
+				m.streamSelection = m.buildStreamSelection()
+				return m, m.advanceFromStreams()
+			case "esc":
+				m.screen = screenFormat
+				return m, nil
+			case "ctrl+c":
+				m.canceled = true
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+
+	case screenLadder:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "up", "k":
+				if m.ladderCursor > 0 {
+					m.ladderCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.ladderCursor < len(ladderCandidates)-1 {
+					m.ladderCursor++
+				}
+				return m, nil
+			case " ", "x":
+				m.ladderEnabled[m.ladderCursor] = !m.ladderEnabled[m.ladderCursor]
+				return m, nil
+			case "[":
+				m.segmentSeconds = prevSegmentSeconds(m.segmentSeconds)
+				return m, nil
+			case "]":
+				m.segmentSeconds = nextSegmentSeconds(m.segmentSeconds)
+				return m, nil
+			case "enter":
+				var ladder []Rung
+				for i, on := range m.ladderEnabled {
+					if on {
+						ladder = append(ladder, ladderCandidates[i])
+					}
+				}
+				if len(ladder) == 0 {
+					ladder = []Rung{ladderCandidates[0]}
+				}
+				applyLadderChoice(m.format, ladder, m.segmentSeconds)
+				m.output = defaultOutputPath(m.value, m.format)
+				m.previewText = ""
+				m.screen = screenConfirm
+				return m, generatePreviewCmd(m.value, PresetByID(activePresets, m.format))
+			case "esc":
+				// hls/dash always skip screenStreams (see
+				// presetUsesStreamSelection), so there's nothing probed to
+				// go back to there -- return to screenFormat instead.
+				m.screen = screenFormat
+				return m, nil
+			case "ctrl+c":
+				m.canceled = true
+				return m, tea.Quit
+			}
+		}
+		return m, nil
+
 	case screenConfirm:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -206,15 +492,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// ensure progress starts at 0
 				m.percent = 0
 				m.progress.SetPercent(0)
-				return m, startConversionCmd(m.value, m.output, m.format)
+				return m, startConversionCmd(0, m.value, m.output, m.format, m.streamSelection)
 			case tea.KeyEsc:
-				// go back to format selection
-				m.screen = screenFormat
+				// go back a step: ladder for hls/dash, streams otherwise --
+				// unless this preset skipped screenStreams entirely, in
+				// which case there's nothing probed to show there.
+				preset := PresetByID(activePresets, m.format)
+				switch {
+				case preset.Container == "hls" || preset.Container == "dash":
+					m.screen = screenLadder
+				case !presetUsesStreamSelection(preset):
+					m.screen = screenFormat
+				default:
+					m.screen = screenStreams
+				}
 				return m, nil
 			case tea.KeyCtrlC:
 				m.canceled = true
 				return m, tea.Quit
 			}
+		case previewMsg:
+			if msg.err == nil {
+				m.previewText = msg.text
+			}
+			return m, nil
 		}
 		// nothing else to update in confirm screen
 		return m, nil
@@ -235,41 +536,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 
 		case progressMsg:
-			m.percent = float64(msg)
+			m.percent = msg.percent
 			m.progress.SetPercent(m.percent)
 			pm, _ := m.progress.Update(nil) // get updated tea.Model and cmd
 			if p, ok := pm.(progress.Model); ok {
 				m.progress = p
 			}
-			return m, listen(m.progressChan)
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
 
 		case ffmpegStatusMsg:
-			m.lastStatus = string(msg)
-			return m, listen(m.progressChan)
+			m.lastStatus = msg.status
+			if msg.status == "FINISHED_OK" {
+				m.handledInTUI = true
+				m.screen = screenDone
+				return m, nil
+			}
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
 
 		case ffmpegErrMsg:
-			m.err = error(msg)
+			m.err = msg.err
 			m.screen = screenError
 			return m, nil
 
 		case startedMsg:
-			// store cancel + cmd
-			m.cancelFn = msg.cancel
-			m.cmd = msg.cmd
-			m.progressChan = msg.ch
+			// store cancel + cmd, keyed by job ID (0 for the single-file run)
+			m.cancelFns[msg.jobID] = msg.cancel
+			m.cmds[msg.jobID] = msg.cmd
+			m.jobChans[msg.jobID] = msg.ch
 			// start listening for progress/status messages from the channel
-			return m, listen(m.progressChan)
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
 
 		case tea.KeyMsg:
 			// allow cancel during running
 			if msg.String() == "ctrl+c" || msg.String() == "esc" {
 				// cancel the ffmpeg process
-				if m.cancelFn != nil {
-					m.cancelFn()
+				if cancel := m.cancelFns[0]; cancel != nil {
+					cancel()
 				}
 				// kill if still running
-				if m.cmd != nil && m.cmd.Process != nil {
-					_ = m.cmd.Process.Kill()
+				if cmd := m.cmds[0]; cmd != nil && cmd.Process != nil {
+					_ = cmd.Process.Kill()
 				}
 				m.canceled = true
 				return m, tea.Quit
@@ -283,6 +589,99 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 
+	case screenQueue:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "up", "k":
+				if m.queueCursor > 0 {
+					m.queueCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.queueCursor < len(m.queue.Jobs)-1 {
+					m.queueCursor++
+				}
+				return m, nil
+			case "c":
+				if m.queueCursor < len(m.queue.Jobs) {
+					m.cancelJob(m.queue.Jobs[m.queueCursor])
+				}
+				return m, nil
+			case "C":
+				for _, j := range m.queue.Jobs {
+					m.cancelJob(j)
+				}
+				return m, nil
+			case "ctrl+c", "q":
+				for _, j := range m.queue.Jobs {
+					m.cancelJob(j)
+				}
+				// quitting after the whole batch has finished isn't a cancel:
+				// leave it to main's handledInTUI branch.
+				m.canceled = !m.handledInTUI
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case progressMsg:
+			if job := m.queue.ByID(msg.jobID); job != nil {
+				job.Percent = msg.percent
+				job.Progress.SetPercent(msg.percent)
+			}
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
+
+		case ffmpegStatusMsg:
+			job := m.queue.ByID(msg.jobID)
+			if job == nil {
+				return m, nil
+			}
+			job.LastStatus = msg.status
+			if msg.status == "FINISHED_OK" {
+				job.Status = JobDone
+				delete(m.jobChans, msg.jobID)
+				delete(m.cancelFns, msg.jobID)
+				delete(m.cmds, msg.jobID)
+				if m.queue.Done() {
+					m.handledInTUI = true
+				}
+				return m, m.startNextJobs()
+			}
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
+
+		case ffmpegErrMsg:
+			job := m.queue.ByID(msg.jobID)
+			if job == nil {
+				return m, nil
+			}
+			if job.Status != JobCanceled {
+				job.Status = JobFailed
+			}
+			job.Err = msg.err
+			delete(m.jobChans, msg.jobID)
+			delete(m.cancelFns, msg.jobID)
+			delete(m.cmds, msg.jobID)
+			if m.queue.Done() {
+				m.handledInTUI = true
+			}
+			return m, m.startNextJobs()
+
+		case startedMsg:
+			m.cancelFns[msg.jobID] = msg.cancel
+			m.cmds[msg.jobID] = msg.cmd
+			m.jobChans[msg.jobID] = msg.ch
+			return m, listen(msg.jobID, m.jobChans[msg.jobID])
+		}
+		return m, nil
+
+	case screenSettings:
+		switch msg.(type) {
+		case tea.KeyMsg:
+			m.screen = screenPicker
+			return m, nil
+		}
+		return m, nil
+
 	case screenError:
 		// show error until keypress
 		switch msg.(type) {
@@ -306,6 +705,225 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// streamRowKind identifies which part of screenStreams a streamRow refers
+// to: one of the probed streams, or one of the trailing downmix-channels and
+// sample-rate controls.
+type streamRowKind int
+
+const (
+	rowVideo streamRowKind = iota
+	rowAudio
+	rowSubtitle
+	rowDownmix
+	rowSampleRate
+)
+
+// streamRow is one line in screenStreams' list: idx indexes into
+// m.mediaInfo.Video/Audio/Subtitle and is unused for rowDownmix/rowSampleRate.
+type streamRow struct {
+	kind streamRowKind
+	idx  int
+}
+
+// streamRows lists every row screenStreams should navigate over, in the
+// order they're rendered: video streams, then audio, then subtitle, then
+// the downmix control, then the sample-rate control.
+func (m *model) streamRows() []streamRow {
+	if m.mediaInfo == nil {
+		return nil
+	}
+	var rows []streamRow
+	for i := range m.mediaInfo.Video {
+		rows = append(rows, streamRow{kind: rowVideo, idx: i})
+	}
+	for i := range m.mediaInfo.Audio {
+		rows = append(rows, streamRow{kind: rowAudio, idx: i})
+	}
+	for i := range m.mediaInfo.Subtitle {
+		rows = append(rows, streamRow{kind: rowSubtitle, idx: i})
+	}
+	rows = append(rows, streamRow{kind: rowDownmix})
+	rows = append(rows, streamRow{kind: rowSampleRate})
+	return rows
+}
+
+// streamRowLabel renders one screenStreams row, including its checkbox (or
+// the current choice, for the downmix and sample-rate rows).
+func (m *model) streamRowLabel(row streamRow) string {
+	switch row.kind {
+	case rowVideo:
+		v := m.mediaInfo.Video[row.idx]
+		return fmt.Sprintf("%s video #%d: %s %dx%d %s", checkbox(m.videoKeep[row.idx]), v.Index, v.CodecName, v.Width, v.Height, v.Language)
+	case rowAudio:
+		a := m.mediaInfo.Audio[row.idx]
+		return fmt.Sprintf("%s audio #%d: %s %s %dch %s", checkbox(m.audioKeep[row.idx]), a.Index, a.CodecName, a.ChannelLayout, a.Channels, a.Language)
+	case rowSubtitle:
+		sub := m.mediaInfo.Subtitle[row.idx]
+		return fmt.Sprintf("%s subtitle #%d: %s %s", checkbox(m.subKeep[row.idx]), sub.Index, sub.CodecName, sub.Language)
+	case rowDownmix:
+		choice := downmixOptions[m.downmixIdx]
+		if choice == "" {
+			choice = "source"
+		} else {
+			choice += " channels"
+		}
+		return "downmix audio to: " + choice
+	default: // rowSampleRate
+		choice := sampleRateOptions[m.sampleRateIdx]
+		if choice == "" {
+			choice = "source"
+		} else {
+			choice += " Hz"
+		}
+		return "resample audio to: " + choice
+	}
+}
+
+func checkbox(on bool) string {
+	if on {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// toggleStreamRow flips a stream's keep flag, or cycles the downmix/sample-rate
+// choice if row is one of the trailing option rows.
+func (m *model) toggleStreamRow(row streamRow) {
+	switch row.kind {
+	case rowVideo:
+		m.videoKeep[row.idx] = !m.videoKeep[row.idx]
+	case rowAudio:
+		m.audioKeep[row.idx] = !m.audioKeep[row.idx]
+	case rowSubtitle:
+		m.subKeep[row.idx] = !m.subKeep[row.idx]
+	case rowDownmix:
+		m.downmixIdx = (m.downmixIdx + 1) % len(downmixOptions)
+	case rowSampleRate:
+		m.sampleRateIdx = (m.sampleRateIdx + 1) % len(sampleRateOptions)
+	}
+}
+
+// cycleStreamRow steps the downmix/sample-rate choice for row by delta
+// (+1/-1), clamped to the option list's bounds. It's a no-op for rows that
+// aren't one of those two option rows.
+func (m *model) cycleStreamRow(row streamRow, delta int) {
+	switch row.kind {
+	case rowDownmix:
+		m.downmixIdx = clampIdx(m.downmixIdx+delta, len(downmixOptions))
+	case rowSampleRate:
+		m.sampleRateIdx = clampIdx(m.sampleRateIdx+delta, len(sampleRateOptions))
+	}
+}
+
+// clampIdx keeps an option-list index within [0, n).
+func clampIdx(idx, n int) int {
+	if idx < 0 {
+		return 0
+	}
+	if idx >= n {
+		return n - 1
+	}
+	return idx
+}
+
+// buildStreamSelection turns the screenStreams keep flags/downmix/sample-rate
+// choices into the StreamSelection startConversionCmd will inject as
+// -map/-ac/-ar args.
+func (m *model) buildStreamSelection() StreamSelection {
+	var sel StreamSelection
+	for i, keep := range m.videoKeep {
+		if keep {
+			sel.VideoStreams = append(sel.VideoStreams, i)
+		}
+	}
+	for i, keep := range m.audioKeep {
+		if keep {
+			sel.AudioStreams = append(sel.AudioStreams, i)
+		}
+	}
+	for i, keep := range m.subKeep {
+		if keep {
+			sel.SubtitleStreams = append(sel.SubtitleStreams, i)
+		}
+	}
+	sel.DownmixChannels = downmixOptions[m.downmixIdx]
+	sel.SampleRateHz = sampleRateOptions[m.sampleRateIdx]
+	return sel
+}
+
+// advanceFromStreams moves on from screenStreams the same way screenFormat
+// used to decide directly: hls/dash presets need the ladder screen first,
+// everything else goes straight to confirm.
+func (m *model) advanceFromStreams() tea.Cmd {
+	preset := PresetByID(activePresets, m.format)
+	if preset.Container == "hls" || preset.Container == "dash" {
+		m.initLadderScreen(preset)
+		m.screen = screenLadder
+		return nil
+	}
+	m.output = defaultOutputPath(m.value, m.format)
+	m.previewText = ""
+	m.screen = screenConfirm
+	return generatePreviewCmd(m.value, preset)
+}
+
+// initLadderScreen seeds screenLadder's state from preset's current ladder
+// (or the candidate defaults, for a freshly-selected hls/dash preset).
+func (m *model) initLadderScreen(preset Preset) {
+	ladder := preset.Ladder
+	if len(ladder) == 0 {
+		ladder = defaultLadder()
+	}
+	m.ladderCursor = 0
+	m.ladderEnabled = make([]bool, len(ladderCandidates))
+	for i, c := range ladderCandidates {
+		for _, r := range ladder {
+			if r.Height == c.Height {
+				m.ladderEnabled[i] = true
+				break
+			}
+		}
+	}
+	m.segmentSeconds = preset.SegmentSeconds
+	if m.segmentSeconds <= 0 {
+		m.segmentSeconds = defaultSegmentSeconds
+	}
+}
+
+// startNextJobs fills any free worker slots with pending jobs, marking each
+// as playing and returning a batched tea.Cmd that starts them all.
+func (m *model) startNextJobs() tea.Cmd {
+	var cmds []tea.Cmd
+	for m.queue.FreeSlots() > 0 {
+		job := m.queue.NextPending()
+		if job == nil {
+			break
+		}
+		job.Status = JobPlaying
+		cmds = append(cmds, startConversionCmd(job.ID, job.Input, job.Output, job.Format, job.Selection))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(cmds...)
+}
+
+// cancelJob stops a running job, or marks a pending one as skipped.
+func (m *model) cancelJob(job *Job) {
+	switch job.Status {
+	case JobPlaying:
+		if cancel := m.cancelFns[job.ID]; cancel != nil {
+			cancel()
+		}
+		if cmd := m.cmds[job.ID]; cmd != nil && cmd.Process != nil {
+			_ = cmd.Process.Kill()
+		}
+		job.Status = JobCanceled
+	case JobAhead:
+		job.Status = JobCanceled
+	}
+}
+
 func (m model) View() string {
 	switch m.screen {
 	case screenPicker:
@@ -322,20 +940,69 @@ func (m model) View() string {
 			s.WriteString("Selected file: " + m.filepicker.Styles.Selected.Render(m.value))
 		}
 		s.WriteString("\n\n" + m.filepicker.View() + "\n")
+		if len(m.selectedInputs) > 0 {
+			s.WriteString(fmt.Sprintf(
+				"\n  Queued for batch (%d): press f to continue, d to add a whole directory\n",
+				len(m.selectedInputs),
+			))
+		}
+		s.WriteString("\n  (s for settings)\n")
 		return s.String()
 
 	case screenFormat:
+		hint := "(Esc to go back, Enter to confirm selection)"
+		if len(m.selectedInputs) > 1 {
+			hint = fmt.Sprintf("(%d files queued; Esc to go back, Enter to start the batch)", len(m.selectedInputs))
+		}
 		return fmt.Sprintf(
 			"Selected input: %s\n\n%s\n\n%s\n",
 			m.value,
 			m.formatList.View(),
-			"(Esc to go back, Enter to confirm selection)",
+			hint,
 		)
 
+	case screenStreams:
+		if m.mediaInfo == nil {
+			return "Probing streams...\n"
+		}
+		var s strings.Builder
+		s.WriteString("Pick which streams to keep:\n\n")
+		rows := m.streamRows()
+		for i, row := range rows {
+			cursor := "  "
+			if i == m.streamCursor {
+				cursor = "> "
+			}
+			s.WriteString(cursor + m.streamRowLabel(row) + "\n")
+		}
+		s.WriteString("\n(space/x toggle row, [/] change downmix/sample rate, Enter to confirm, Esc to go back)\n")
+		return s.String()
+
+	case screenLadder:
+		var s strings.Builder
+		s.WriteString(fmt.Sprintf("Adaptive ladder for %s (segment length: %ds)\n\n", m.format, m.segmentSeconds))
+		for i, c := range ladderCandidates {
+			box := "[ ]"
+			if m.ladderEnabled[i] {
+				box = "[x]"
+			}
+			cursor := "  "
+			if i == m.ladderCursor {
+				cursor = "> "
+			}
+			s.WriteString(fmt.Sprintf("%s%s %dp  (video %s, audio %s)\n", cursor, box, c.Height, c.VideoBitrate, c.AudioBitrate))
+		}
+		s.WriteString("\n(space/x toggle rung, [/] segment length, Enter to confirm, Esc to go back)\n")
+		return s.String()
+
 	case screenConfirm:
+		var preview string
+		if m.previewText != "" {
+			preview = "\n" + m.previewText + "\n"
+		}
 		return fmt.Sprintf(
-			"Ready to convert:\n\n  input:  %s\n  format: %s\n  output: %s\n\nPress Enter to start conversion, Esc to go back, Ctrl+C to cancel.\n",
-			m.value, m.format, m.output,
+			"Ready to convert:\n\n  input:  %s\n  format: %s\n  output: %s\n%s\nPress Enter to start conversion, Esc to go back, Ctrl+C to cancel.\n",
+			m.value, m.format, m.output, preview,
 		)
 
 	case screenRunning:
@@ -344,6 +1011,34 @@ func (m model) View() string {
 		return "\n" +
 			pad + m.progress.View() + "\n\n"
 
+	case screenQueue:
+		var s strings.Builder
+		s.WriteString("Batch queue:\n\n")
+		for i, job := range m.queue.Jobs {
+			cursor := "  "
+			if i == m.queueCursor {
+				cursor = "> "
+			}
+			s.WriteString(fmt.Sprintf(
+				"%s[%d] %-8s %s\n    %s\n",
+				cursor, job.ID, jobStatusLabel(job.Status), filepath.Base(job.Input), job.Progress.View(),
+			))
+			if job.LastStatus != "" && job.Status == JobPlaying {
+				s.WriteString("    " + job.LastStatus + "\n")
+			}
+			if job.Err != nil {
+				s.WriteString("    error: " + job.Err.Error() + "\n")
+			}
+		}
+		s.WriteString("\n(c cancel selected, C cancel all, q quit)\n")
+		return s.String()
+
+	case screenSettings:
+		return fmt.Sprintf(
+			"Settings:\n\n  ffmpeg:  %s\n           %s\n\n  ffprobe: %s\n           %s\n\n(press any key to go back)\n",
+			activeFFPaths.FFmpeg, m.ffmpegVersion, activeFFPaths.FFprobe, m.ffprobeVersion,
+		)
+
 	case screenError:
 		return fmt.Sprintf("Error: %v\n\n(press any key to go back)\n", m.err)
 
@@ -356,6 +1051,24 @@ func (m model) View() string {
 	}
 }
 
+// jobStatusLabel renders a JobStatus as the short word shown in the queue view.
+func jobStatusLabel(s JobStatus) string {
+	switch s {
+	case JobAhead:
+		return "queued"
+	case JobPlaying:
+		return "running"
+	case JobDone:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
 func RunTUI() (model, error) {
 	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
 	final, err := p.Run()
@@ -0,0 +1,214 @@
+// presets.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// activePresets is the preset set resolved once at startup by initialModel:
+// built-ins, merged with whatever hardware encoders are available, merged
+// with the user's own presets.json. buildFFmpegArgs/defaultOutputPath read
+// from it by ID so they don't need a preset threaded through every caller.
+var activePresets []Preset
+
+// Preset describes one selectable conversion target: the encoder/container
+// settings plus the output naming convention for it. Keeping both on the
+// same struct is what lets buildFFmpegArgs and defaultOutputPath each
+// work from just a preset ID instead of threading extra naming state
+// alongside it.
+type Preset struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	Container  string `json:"container"`
+	VideoCodec string `json:"video_codec,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+	PixFmt     string `json:"pix_fmt,omitempty"`
+
+	// Bitrate applies to audio (-b:a) when CRF is also set or there's no
+	// video codec, and to video (-b:v) otherwise -- the same convention
+	// used by buildArgsForPreset.
+	Bitrate    string `json:"bitrate,omitempty"`
+	CRF        string `json:"crf,omitempty"`
+	SampleRate string `json:"sample_rate,omitempty"`
+	Channels   string `json:"channels,omitempty"`
+
+	ExtraArgs []string `json:"extra_args,omitempty"`
+
+	// Ladder and SegmentSeconds only apply to the "hls"/"dash" containers:
+	// they describe the adaptive-bitrate rungs and segment duration for a
+	// multi-bitrate output instead of a single encoded file. See hls.go.
+	Ladder         []Rung `json:"ladder,omitempty"`
+	SegmentSeconds int    `json:"segment_seconds,omitempty"`
+
+	// SheetRows/SheetCols only apply to the "sheet" container: the contact
+	// sheet's grid dimensions. See thumbnails.go.
+	SheetRows int `json:"sheet_rows,omitempty"`
+	SheetCols int `json:"sheet_cols,omitempty"`
+
+	OutputSuffix string `json:"output_suffix"`
+	OutputExt    string `json:"output_ext"`
+}
+
+// builtinPresets are shipped so the app has something to offer with no
+// config file at all.
+func builtinPresets() []Preset {
+	return []Preset{
+		{
+			ID: "h264", Title: "H.264 (MP4)", Description: "Smaller files, generally supported",
+			Container: "mp4", VideoCodec: "libx264", AudioCodec: "aac",
+			CRF: "20", Bitrate: "192k", ExtraArgs: []string{"-preset", "medium"},
+			OutputSuffix: "_h264", OutputExt: ".mp4",
+		},
+		{
+			ID: "prores", Title: "Apple ProRes (MOV)", Description: "Edit-friendly, large files (ProRes)",
+			Container: "mov", VideoCodec: "prores_ks", AudioCodec: "pcm_s16le", PixFmt: "yuv422p10le",
+			ExtraArgs:    []string{"-profile:v", "3"},
+			OutputSuffix: "_prores", OutputExt: ".mov",
+		},
+		{
+			ID: "dnxhd", Title: "DNxHD / DNxHR (MXF)", Description: "Avid-style mezzanine codec",
+			Container: "mxf", VideoCodec: "dnxhd", AudioCodec: "pcm_s16le", PixFmt: "yuv422p",
+			Bitrate:      "185M",
+			OutputSuffix: "_dnx", OutputExt: ".mxf",
+		},
+		{
+			ID: "wav", Title: "WAV 48kHz (Audio only)", Description: "Export audio only as WAV",
+			Container: "wav", AudioCodec: "pcm_s16le", SampleRate: "48000", Channels: "2",
+			OutputSuffix: "_48k", OutputExt: ".wav",
+		},
+		{
+			ID: "hls", Title: "HLS (adaptive)", Description: "Segmented multi-bitrate ladder with a master playlist",
+			Container: "hls", Ladder: defaultLadder(), SegmentSeconds: defaultSegmentSeconds,
+			OutputSuffix: "_hls",
+		},
+		{
+			ID: "dash", Title: "DASH (adaptive)", Description: "Segmented multi-bitrate ladder with an MPD manifest",
+			Container: "dash", Ladder: defaultLadder(), SegmentSeconds: defaultSegmentSeconds,
+			OutputSuffix: "_dash",
+		},
+		{
+			ID: "sheet", Title: "Contact Sheet (JPEG)", Description: "Grid of representative frames as a single image",
+			Container: "sheet", SheetRows: 3, SheetCols: 3,
+			OutputSuffix: "_sheet", OutputExt: ".jpg",
+		},
+	}
+}
+
+// hwPresets converts the hardware encoders that passed probeAvailableEncoders
+// into presets so they flow through the same loading/merging/validation
+// path as the built-ins, instead of being special-cased in the TUI.
+func hwPresets(available map[string]bool) []Preset {
+	var out []Preset
+	for _, f := range availableHWFormats(available) {
+		out = append(out, Preset{
+			ID: f.id, Title: f.title, Description: f.desc,
+			Container: "mp4", VideoCodec: f.ffmpegName, AudioCodec: "aac",
+			OutputSuffix: "_" + f.id, OutputExt: ".mp4",
+		})
+	}
+	return out
+}
+
+// userPresetsPath resolves $XDG_CONFIG_HOME/daviconverter/presets.json (via
+// os.UserConfigDir, which already honours XDG_CONFIG_HOME on Linux).
+func userPresetsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "daviconverter", "presets.json")
+}
+
+// loadUserPresets reads user-defined presets from disk. A missing file
+// simply means there's nothing to merge in, not an error.
+func loadUserPresets(path string) ([]Preset, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var presets []Preset
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, fmt.Errorf("presets.json: %w", err)
+	}
+	return presets, nil
+}
+
+// mergePresets overlays override entries onto base by ID: a matching ID
+// replaces the base preset in place, a new ID is appended at the end.
+func mergePresets(base, overrides []Preset) []Preset {
+	merged := make([]Preset, len(base))
+	copy(merged, base)
+
+	for _, o := range overrides {
+		replaced := false
+		for i, b := range merged {
+			if b.ID == o.ID {
+				merged[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+// LoadPresets assembles the full preset list: built-ins, whatever hardware
+// encoders are available on this host, and the user's overrides/additions
+// from presets.json. A broken config file is ignored rather than failing
+// startup -- the built-ins/hardware presets still work.
+func LoadPresets(available map[string]bool) []Preset {
+	all := append(builtinPresets(), hwPresets(available)...)
+
+	userPresets, err := loadUserPresets(userPresetsPath())
+	if err != nil || len(userPresets) == 0 {
+		return all
+	}
+	return mergePresets(all, userPresets)
+}
+
+// PresetByID looks up a preset by ID, falling back to "h264" (or, if even
+// that's missing, a hardcoded equivalent) so callers never have to check ok.
+func PresetByID(presets []Preset, id string) Preset {
+	for _, p := range presets {
+		if p.ID == id {
+			return p
+		}
+	}
+	for _, p := range presets {
+		if p.ID == "h264" {
+			return p
+		}
+	}
+	return builtinPresets()[0]
+}
+
+// formatValidator checks formatFlag against the loaded preset set.
+func formatValidator(presets []Preset, formatFlag *string) (string, error) {
+	format := strings.ToLower(*formatFlag)
+	for _, p := range presets {
+		if p.ID == format {
+			return format, nil
+		}
+	}
+
+	ids := make([]string, len(presets))
+	for i, p := range presets {
+		ids[i] = p.ID
+	}
+	return format, fmt.Errorf("invalid format: %s. valid formats: %s", format, strings.Join(ids, ", "))
+}
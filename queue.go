@@ -0,0 +1,127 @@
+// queue.go
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/progress"
+)
+
+// JobStatus is the state machine a queued conversion moves through: waiting
+// for a worker slot, actively transcoding, then one of three end states so
+// the queue view can tell "finished cleanly" apart from "ffmpeg errored" and
+// "the user canceled it".
+type JobStatus int
+
+const (
+	JobAhead    JobStatus = iota // waiting for a worker slot
+	JobPlaying                   // actively being transcoded
+	JobDone                      // finished successfully
+	JobFailed                    // ffmpeg/ffprobe returned an error
+	JobCanceled                  // canceled by the user before finishing
+)
+
+// Job is a single queued conversion.
+type Job struct {
+	ID     int
+	Input  string
+	Output string
+	Format string
+
+	// Selection is the stream mapping to apply, if any. Batch jobs leave
+	// this at its zero value -- screenStreams only runs for a single-file
+	// conversion, same as screenLadder.
+	Selection StreamSelection
+
+	Status     JobStatus
+	Percent    float64
+	LastStatus string
+	Err        error
+
+	Progress progress.Model
+}
+
+// Queue holds every job enqueued in this session plus how many of them may
+// run at the same time.
+type Queue struct {
+	Jobs        []*Job
+	Parallelism int
+
+	nextID int
+}
+
+// NewQueue creates an empty queue that will run up to parallelism jobs at
+// once. A parallelism below 1 is treated as 1.
+func NewQueue(parallelism int) *Queue {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	return &Queue{Parallelism: parallelism}
+}
+
+// Enqueue adds a new pending job for input/format and returns it.
+func (q *Queue) Enqueue(input, format string) *Job {
+	q.nextID++
+	pb := progress.New()
+	pb.SetPercent(0)
+
+	job := &Job{
+		ID:       q.nextID,
+		Input:    input,
+		Output:   defaultOutputPath(input, format),
+		Format:   format,
+		Status:   JobAhead,
+		Progress: pb,
+	}
+	q.Jobs = append(q.Jobs, job)
+	return job
+}
+
+// ByID returns the job with the given ID, or nil if it isn't in the queue.
+func (q *Queue) ByID(id int) *Job {
+	for _, j := range q.Jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// Running returns every job currently being transcoded.
+func (q *Queue) Running() []*Job {
+	var out []*Job
+	for _, j := range q.Jobs {
+		if j.Status == JobPlaying {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// NextPending returns the next job waiting for a worker slot, or nil.
+func (q *Queue) NextPending() *Job {
+	for _, j := range q.Jobs {
+		if j.Status == JobAhead {
+			return j
+		}
+	}
+	return nil
+}
+
+// FreeSlots reports how many more jobs can be started right now.
+func (q *Queue) FreeSlots() int {
+	free := q.Parallelism - len(q.Running())
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+// Done reports whether the queue holds at least one job and every job has
+// left the Ahead/Playing states; an empty queue is not Done.
+func (q *Queue) Done() bool {
+	for _, j := range q.Jobs {
+		if j.Status == JobAhead || j.Status == JobPlaying {
+			return false
+		}
+	}
+	return len(q.Jobs) > 0
+}
@@ -0,0 +1,366 @@
+// thumbnails.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Thumbnails generates and caches single-frame PNG previews of a video at
+// specific timestamps, and stitches a set of them into one contact-sheet
+// image via ffmpeg's tile= filter. Caching by (path, mtime, size, timestamp)
+// means repeat confirm-screen visits for the same file don't re-run ffmpeg
+// just to redraw a preview that hasn't changed.
+type Thumbnails struct {
+	CacheDir string
+}
+
+// NewThumbnails resolves the PNG cache directory under the OS temp dir.
+func NewThumbnails() *Thumbnails {
+	return &Thumbnails{CacheDir: filepath.Join(os.TempDir(), "daviconverter-thumbs")}
+}
+
+// cacheKey identifies one cached thumbnail by the source file's identity
+// (path, mtime, size) plus the timestamp within it, so a re-encoded or
+// replaced file never serves a stale thumbnail.
+func (t *Thumbnails) cacheKey(path string, mtime time.Time, size int64, atSeconds float64) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%.3f", path, mtime.UnixNano(), size, atSeconds)))
+	return fmt.Sprintf("%x.png", h)
+}
+
+// thumbTimestamps picks count timestamps evenly spaced across dur, staying
+// clear of the very first/last instants where ffmpeg is more likely to seek
+// past the end or land on a black frame.
+func thumbTimestamps(dur float64, count int) []float64 {
+	if count <= 0 {
+		return nil
+	}
+	out := make([]float64, count)
+	step := dur / float64(count+1)
+	for i := range out {
+		out[i] = step * float64(i+1)
+	}
+	return out
+}
+
+// Generate returns the cached (generating on miss) PNG paths for count
+// thumbnails spread evenly across path's duration.
+func (t *Thumbnails) Generate(path string, count int) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	dur, err := probeDuration(path)
+	if err != nil || dur <= 0 {
+		return nil, fmt.Errorf("probe duration: %w", err)
+	}
+	if err := os.MkdirAll(t.CacheDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, ts := range thumbTimestamps(dur, count) {
+		dest := filepath.Join(t.CacheDir, t.cacheKey(path, info.ModTime(), info.Size(), ts))
+		if _, err := os.Stat(dest); err == nil {
+			out = append(out, dest)
+			continue
+		}
+		if err := t.extractFrame(path, ts, dest); err != nil {
+			return nil, err
+		}
+		out = append(out, dest)
+	}
+	return out, nil
+}
+
+// extractFrame seeks to atSeconds, grabs exactly one frame, scales it down,
+// and writes it straight to dest as a PNG.
+func (t *Thumbnails) extractFrame(path string, atSeconds float64, dest string) error {
+	args := []string{
+		"-y",
+		"-ss", fmt.Sprintf("%.3f", atSeconds),
+		"-i", path,
+		"-frames:v", "1",
+		"-vf", "scale=320:-1",
+		dest,
+	}
+	return exec.Command(activeFFPaths.FFmpeg, args...).Run()
+}
+
+// GenerateContactSheet generates rows*cols thumbnails for path and stitches
+// them into a single PNG grid: the cached frames are concatenated into one
+// video stream and arranged with ffmpeg's tile= filter. Returns the sheet's
+// cache path. Used for the confirm-screen preview of a "sheet" preset --
+// the preset's own output goes through buildSheetArgs instead, since that
+// produces the file fresh each time rather than serving it from cache.
+func (t *Thumbnails) GenerateContactSheet(path string, rows, cols int) (string, error) {
+	count := rows * cols
+	if count <= 0 {
+		return "", fmt.Errorf("rows and cols must be positive")
+	}
+
+	frames, err := t.Generate(path, count)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	sheetPath := filepath.Join(t.CacheDir, strings.TrimSuffix(t.cacheKey(path, info.ModTime(), info.Size(), -float64(count)), ".png")+"_sheet.png")
+	if _, err := os.Stat(sheetPath); err == nil {
+		return sheetPath, nil
+	}
+
+	args := []string{"-y"}
+	for _, f := range frames {
+		args = append(args, "-i", f)
+	}
+
+	var refs strings.Builder
+	for i := range frames {
+		fmt.Fprintf(&refs, "[%d:v]", i)
+	}
+	filter := fmt.Sprintf("%sconcat=n=%d:v=1:a=0[c];[c]tile=%dx%d", refs.String(), len(frames), cols, rows)
+
+	args = append(args, "-filter_complex", filter, "-frames:v", "1", sheetPath)
+	if err := exec.Command(activeFFPaths.FFmpeg, args...).Run(); err != nil {
+		return "", fmt.Errorf("contact sheet: %w", err)
+	}
+	return sheetPath, nil
+}
+
+// buildSheetArgs builds the single-pass ffmpeg invocation behind the "sheet"
+// preset: it samples representative frames straight out of the source and
+// tiles them in one command, the same contact-sheet feature
+// GenerateContactSheet stitches from individually-cached frames, just
+// producing the preset's actual output fresh each time instead of serving
+// it from cache.
+func buildSheetArgs(preset Preset, inputPath, outputPath string) []string {
+	rows, cols := preset.SheetRows, preset.SheetCols
+	if rows <= 0 {
+		rows = 3
+	}
+	if cols <= 0 {
+		cols = 3
+	}
+	vf := fmt.Sprintf("thumbnail=300,scale=320:-1,tile=%dx%d", cols, rows)
+	return []string{
+		"-y", "-i", inputPath,
+		"-vf", vf,
+		"-frames:v", "1",
+		outputPath,
+	}
+}
+
+// previewMode reads DAVICONVERTER_PREVIEW ("ascii", "sixel" or "kitty") to
+// decide whether/how the confirm screen renders a preview of the input
+// file. The feature defaults off: most terminals don't support sixel or
+// kitty graphics, and rendering a thumbnail costs an extra ffmpeg call.
+func previewMode() string {
+	switch strings.ToLower(os.Getenv("DAVICONVERTER_PREVIEW")) {
+	case "ascii":
+		return "ascii"
+	case "sixel":
+		return "sixel"
+	case "kitty":
+		return "kitty"
+	default:
+		return ""
+	}
+}
+
+// RenderPreview generates a preview of path and renders it in whatever mode
+// previewMode() selects. For a "sheet" preset it previews the same stitched
+// contact sheet the conversion is about to produce (via GenerateContactSheet);
+// every other preset previews a single frame. An empty string means the
+// feature is off; a non-nil error means generating the preview itself failed.
+func RenderPreview(path string, preset Preset) (string, error) {
+	mode := previewMode()
+	if mode == "" {
+		return "", nil
+	}
+
+	var frame string
+	if preset.Container == "sheet" {
+		rows, cols := preset.SheetRows, preset.SheetCols
+		if rows <= 0 {
+			rows = 3
+		}
+		if cols <= 0 {
+			cols = 3
+		}
+		sheet, err := NewThumbnails().GenerateContactSheet(path, rows, cols)
+		if err != nil {
+			return "", fmt.Errorf("generate contact sheet: %w", err)
+		}
+		frame = sheet
+	} else {
+		frames, err := NewThumbnails().Generate(path, 1)
+		if err != nil || len(frames) == 0 {
+			return "", fmt.Errorf("generate thumbnail: %w", err)
+		}
+		frame = frames[0]
+	}
+
+	switch mode {
+	case "kitty":
+		return kittyEscape(frame)
+	case "sixel":
+		img, err := decodePNG(frame)
+		if err != nil {
+			return "", err
+		}
+		return sixelEscape(img), nil
+	default: // "ascii"
+		img, err := decodePNG(frame)
+		if err != nil {
+			return "", err
+		}
+		return asciiArt(img, 60), nil
+	}
+}
+
+func decodePNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// asciiArt downsamples img to cols columns (rows follow the image's aspect
+// ratio, halved to compensate for terminal cells being roughly twice as
+// tall as they are wide) and maps luminance onto a fixed character ramp.
+func asciiArt(img image.Image, cols int) string {
+	const ramp = " .:-=+*#%@"
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return ""
+	}
+	if cols > w {
+		cols = w
+	}
+	rows := int(float64(h) / float64(w) * float64(cols) * 0.5)
+	if rows < 1 {
+		rows = 1
+	}
+
+	var out strings.Builder
+	for ry := 0; ry < rows; ry++ {
+		for rx := 0; rx < cols; rx++ {
+			sx := bounds.Min.X + rx*w/cols
+			sy := bounds.Min.Y + ry*h/rows
+			gray := color.GrayModel.Convert(img.At(sx, sy)).(color.Gray).Y
+			idx := int(gray) * (len(ramp) - 1) / 255
+			out.WriteByte(ramp[idx])
+		}
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+// kittyEscape wraps a PNG file's raw bytes in the kitty terminal graphics
+// protocol escape sequence. The base64 payload is split into chunks of at
+// most 4096 bytes (m=1 on every chunk but the last), so a contact sheet fits
+// just as well as a single 320px-wide thumbnail.
+func kittyEscape(pngPath string) (string, error) {
+	data, err := os.ReadFile(pngPath)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	const chunkSize = 4096
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += chunkSize {
+		end, more := i+chunkSize, 1
+		if end >= len(encoded) {
+			end, more = len(encoded), 0
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, encoded[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	b.WriteByte('\n')
+	return b.String(), nil
+}
+
+// sixelEscape renders img as a minimal grayscale DEC sixel image: pixels are
+// quantized to a small palette of gray levels, and each level is emitted as
+// its own sixel pass over the 6-row bands that make up the image.
+func sixelEscape(img image.Image) string {
+	const cols = 80
+	const levels = 8
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return ""
+	}
+	scaledW := cols
+	if scaledW > w {
+		scaledW = w
+	}
+	scaledH := int(float64(h) / float64(w) * float64(scaledW))
+	if scaledH < 1 {
+		scaledH = 1
+	}
+
+	gray := make([][]int, scaledH)
+	for y := 0; y < scaledH; y++ {
+		gray[y] = make([]int, scaledW)
+		for x := 0; x < scaledW; x++ {
+			sx := bounds.Min.X + x*w/scaledW
+			sy := bounds.Min.Y + y*h/scaledH
+			v := color.GrayModel.Convert(img.At(sx, sy)).(color.Gray).Y
+			gray[y][x] = int(v) * (levels - 1) / 255
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1bPq\n")
+	for level := 0; level < levels; level++ {
+		pct := level * 100 / (levels - 1)
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", level, pct, pct, pct)
+	}
+	b.WriteByte('\n')
+
+	for band := 0; band*6 < scaledH; band++ {
+		for level := 0; level < levels; level++ {
+			fmt.Fprintf(&b, "#%d", level)
+			for x := 0; x < scaledW; x++ {
+				var bits int
+				for row := 0; row < 6; row++ {
+					y := band*6 + row
+					if y >= scaledH {
+						continue
+					}
+					if gray[y][x] == level {
+						bits |= 1 << uint(row)
+					}
+				}
+				b.WriteByte(byte(63 + bits))
+			}
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+		b.WriteByte('\n')
+	}
+	b.WriteString("\x1b\\\n")
+	return b.String()
+}
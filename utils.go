@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,74 +15,98 @@ import (
 )
 
 func Convert(inputPath, outputPath, format string) error {
-	args := buildFFmpegArgs(inputPath, outputPath, format)
+	args := buildFFmpegArgs(inputPath, outputPath, format, StreamSelection{})
 
-	ffmpegCmd := exec.Command("ffmpeg", args...)
+	ffmpegCmd := exec.Command(activeFFPaths.FFmpeg, args...)
 	ffmpegCmd.Stdout = os.Stdout
 	ffmpegCmd.Stderr = os.Stderr
 
 	return ffmpegCmd.Run()
 }
 
-func buildFFmpegArgs(inputPath, outputPath, format string) []string {
-	switch format {
-	case "h264":
-		return []string{
-			"-y",
-			"-i", inputPath,
-			"-c:v", "libx264",
-			"-preset", "medium",
-			"-crf", "20",
-			"-c:a", "aac",
-			"-b:a", "192k",
-			outputPath,
-		}
-	case "prores":
-		// ProRes HQ
-		return []string{
-			"-y",
-			"-i", inputPath,
-			"-c:v", "prores_ks",
-			"-profile:v", "3",
-			"-pix_fmt", "yuv422p10le",
-			"-c:a", "pcm_s16le",
-			outputPath,
-		}
-	case "dnxhd":
-		// Basic DNxHD example for 1080p
-		return []string{
-			"-y",
-			"-i", inputPath,
-			"-c:v", "dnxhd",
-			"-b:v", "185M",
-			"-pix_fmt", "yuv422p",
-			"-c:a", "pcm_s16le",
-			outputPath,
+// buildFFmpegArgs resolves format (a preset ID) against the loaded preset
+// set and builds the ffmpeg argument list for it. sel is a zero-value
+// StreamSelection{} for callers that don't offer stream mapping (a bare CLI
+// run, batch jobs, hw/HLS/DASH/sheet presets).
+func buildFFmpegArgs(inputPath, outputPath, format string, sel StreamSelection) []string {
+	return buildArgsForPreset(PresetByID(activePresets, format), inputPath, outputPath, sel)
+}
+
+// buildArgsForPreset is a pure function of a Preset, a pair of paths, and a
+// stream selection -- the actual encoder/container knobs live on the Preset,
+// stream mapping lives on sel. sel only applies to the generic branch below:
+// hw/HLS/DASH/sheet outputs have their own fixed mapping.
+func buildArgsForPreset(preset Preset, inputPath, outputPath string, sel StreamSelection) []string {
+	if _, ok := hwEncoderFamily(preset.VideoCodec); ok {
+		return buildHWFFmpegArgs(inputPath, outputPath, preset.VideoCodec)
+	}
+
+	switch preset.Container {
+	case "hls":
+		return buildHLSArgs(preset, inputPath, outputPath)
+	case "dash":
+		return buildDASHArgs(preset, inputPath, outputPath)
+	case "sheet":
+		return buildSheetArgs(preset, inputPath, outputPath)
+	}
+
+	if preset.VideoCodec == "" {
+		// audio-only container (e.g. wav): drop any video picks so we never
+		// emit a video -map alongside -vn, which ffmpeg rejects outright.
+		sel.VideoStreams = nil
+	}
+
+	args := []string{"-y", "-i", inputPath}
+	args = append(args, buildStreamArgs(sel)...)
+
+	if preset.VideoCodec == "" {
+		args = append(args, "-vn")
+	} else {
+		args = append(args, "-c:v", preset.VideoCodec)
+		if preset.PixFmt != "" {
+			args = append(args, "-pix_fmt", preset.PixFmt)
 		}
-	case "wav":
-		// audio-only WAV 48kHz stereo
-		return []string{
-			"-y",
-			"-i", inputPath,
-			"-vn",
-			"-ar", "48000",
-			"-ac", "2",
-			"-c:a", "pcm_s16le",
-			outputPath,
+		if preset.CRF != "" {
+			args = append(args, "-crf", preset.CRF)
 		}
-	default:
-		// fallback to h264
-		return []string{
-			"-y",
-			"-i", inputPath,
-			"-c:v", "libx264",
-			"-preset", "medium",
-			"-crf", "20",
-			"-c:a", "aac",
-			"-b:a", "192k",
-			outputPath,
+	}
+
+	if preset.AudioCodec != "" {
+		args = append(args, "-c:a", preset.AudioCodec)
+	}
+	if preset.SampleRate != "" {
+		args = append(args, "-ar", preset.SampleRate)
+	}
+	if preset.Channels != "" {
+		args = append(args, "-ac", preset.Channels)
+	}
+	if preset.Bitrate != "" {
+		if preset.CRF != "" || preset.VideoCodec == "" {
+			args = append(args, "-b:a", preset.Bitrate)
+		} else {
+			args = append(args, "-b:v", preset.Bitrate)
 		}
 	}
+
+	args = append(args, preset.ExtraArgs...)
+	args = append(args, outputPath)
+	return args
+}
+
+// presetUsesStreamSelection reports whether buildArgsForPreset actually
+// threads a StreamSelection into preset's ffmpeg invocation. hw encoders and
+// the hls/dash/sheet containers have their own fixed mapping, so sending the
+// user through screenStreams for them would just be picking options that get
+// silently discarded.
+func presetUsesStreamSelection(preset Preset) bool {
+	if _, ok := hwEncoderFamily(preset.VideoCodec); ok {
+		return false
+	}
+	switch preset.Container {
+	case "hls", "dash", "sheet":
+		return false
+	}
+	return true
 }
 
 func defaultOutputPath(input string, format string) string {
@@ -92,34 +115,38 @@ func defaultOutputPath(input string, format string) string {
 	ext := filepath.Ext(file)             // gets the extension of the file
 	base := strings.TrimSuffix(file, ext) // removes the extenstion
 
-	var newName string
-	switch format {
-	case "h264":
-		newName = base + "_h264.mp4"
-	case "prores":
-		newName = base + "_prores.mov"
-	case "dnxhd":
-		newName = base + "_dnx.mxf"
-	case "wav":
-		newName = base + "_48k.wav"
-	default:
-		newName = base + "_out.mp4"
+	preset := PresetByID(activePresets, format)
+	if preset.Container == "hls" || preset.Container == "dash" {
+		// adaptive outputs are a directory of segments + a master manifest,
+		// not a single file.
+		return filepath.Join(path, base+preset.OutputSuffix)
 	}
-	return filepath.Join(path, newName)
+	return filepath.Join(path, base+preset.OutputSuffix+preset.OutputExt)
 }
 
-func formatValidator(formatFlag *string) (string, error) {
-	format := strings.ToLower(*formatFlag)
-	switch format {
-	case "h264", "prores", "dnxhd", "wav":
-		return format, nil
-	default:
-		errorMessage := fmt.Sprintf(
-			"invalid format: %s. valid formats: h264, prores, dnxhd, wav",
-			format,
-		)
-		return format, errors.New(errorMessage)
+// scanDirForInputs lists every file directly inside dir whose extension is
+// in allowed (case-insensitive), for the filepicker's "add whole directory"
+// key binding. Subdirectories are not descended into.
+func scanDirForInputs(dir string, allowed []string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
 	}
+
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		for _, a := range allowed {
+			if strings.ToLower(a) == ext {
+				out = append(out, filepath.Join(dir, e.Name()))
+				break
+			}
+		}
+	}
+	return out
 }
 
 func isInputEmpty(inPathFlag string) error {
@@ -130,7 +157,12 @@ func isInputEmpty(inPathFlag string) error {
 	return nil
 }
 
-func startConversionCmd(input, output, format string) tea.Cmd {
+// startConversionCmd launches ffmpeg for a single job. jobID is stamped onto
+// every message sent down ch so the UI can route updates to the right row
+// in the queue even when several jobs are running at once. sel is the
+// stream mapping chosen on screenStreams, or a zero-value for batch jobs
+// (which skip that screen and keep ffmpeg's default mapping).
+func startConversionCmd(jobID int, input, output, format string, sel StreamSelection) tea.Cmd {
 	return func() tea.Msg {
 		ch := make(chan tea.Msg, 32)
 		// probe duration
@@ -138,27 +170,28 @@ func startConversionCmd(input, output, format string) tea.Cmd {
 
 		dur, err := probeDuration(input)
 		if err != nil || dur <= 0 {
-			return ffmpegErrMsg(fmt.Errorf("ffprobe error: %w", err))
+			cancel()
+			return ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("ffprobe error: %w", err)}
 		}
 
-		args := buildFFmpegArgs(input, output, format)
+		args := buildFFmpegArgs(input, output, format, sel)
 		args = append(args, "-progress", "pipe:1", "-nostats")
 
-		cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+		cmd := exec.CommandContext(ctx, activeFFPaths.FFmpeg, args...)
 		stdout, err := cmd.StdoutPipe()
 		if err != nil {
 			cancel()
-			return ffmpegErrMsg(fmt.Errorf("stdout pipe error: %w", err))
+			return ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("stdout pipe error: %w", err)}
 		}
 		stderr, err := cmd.StderrPipe()
 		if err != nil {
 			cancel()
-			return ffmpegErrMsg(fmt.Errorf("stderr pipe error: %w", err))
+			return ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("stderr pipe error: %w", err)}
 		}
 
 		if err := cmd.Start(); err != nil {
 			cancel()
-			return ffmpegErrMsg(fmt.Errorf("ffmpeg start failed: %w", err))
+			return ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("ffmpeg start failed: %w", err)}
 		}
 
 		// send cmd and cancel to UI via tea.Send (we'll piggyback on a goroutine)
@@ -170,7 +203,7 @@ func startConversionCmd(input, output, format string) tea.Cmd {
 				// send short status lines occasionally
 				if strings.Contains(line, "frame=") || strings.Contains(line, "speed=") || strings.Contains(line, "time=") {
 					select {
-					case ch <- ffmpegStatusMsg(line):
+					case ch <- ffmpegStatusMsg{jobID: jobID, status: line}:
 					default:
 					}
 				}
@@ -193,7 +226,7 @@ func startConversionCmd(input, output, format string) tea.Cmd {
 							percent = 1
 						}
 						select {
-						case ch <- progressMsg(percent):
+						case ch <- progressMsg{jobID: jobID, percent: percent}:
 						default:
 						}
 					} else if strings.HasPrefix(line, "out_time=") {
@@ -208,7 +241,7 @@ func startConversionCmd(input, output, format string) tea.Cmd {
 								percent = 1
 							}
 							select {
-							case ch <- progressMsg(percent):
+							case ch <- progressMsg{jobID: jobID, percent: percent}:
 							default:
 							}
 						}
@@ -224,43 +257,37 @@ func startConversionCmd(input, output, format string) tea.Cmd {
 			if err != nil {
 				// if cancelled by context, send cancellation error
 				if ctx.Err() == context.Canceled {
-					ch <- ffmpegErrMsg(fmt.Errorf("conversion canceled"))
+					ch <- ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("conversion canceled")}
 				} else {
-					ch <- ffmpegErrMsg(fmt.Errorf("ffmpeg error: %w", err))
+					ch <- ffmpegErrMsg{jobID: jobID, err: fmt.Errorf("ffmpeg error: %w", err)}
 				}
 				close(ch)
 				return
 			}
 			// success: final progress and status messages
-			ch <- progressMsg(1.0)
-			ch <- ffmpegStatusMsg("FINISHED_OK")
+			ch <- progressMsg{jobID: jobID, percent: 1.0}
+			ch <- ffmpegStatusMsg{jobID: jobID, status: "FINISHED_OK"}
 			close(ch)
 		}()
 
 		// Return a startedMsg so Update can store cancel/cmd and then begin listening.
 		// We'll send the startedMsg immediately (the runtime will deliver it to Update),
 		// and Update should then schedule listen(ch) to start receiving messages.
-		return startedMsg{cancel: cancel, cmd: cmd, ch: ch}
+		return startedMsg{jobID: jobID, cancel: cancel, cmd: cmd, ch: ch}
 	}
 }
 
+// probeDuration is a thin convenience wrapper over Probe for the common case
+// of just needing the duration (progress-bar math, thumbnail timestamps).
 func probeDuration(path string) (float64, error) {
-	// ffprobe -v error -show_entries format=duration -of default=noprint_wrappers=1:nokey=1 path
-	cmd := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration",
-		"-of", "default=noprint_wrappers=1:nokey=1", path)
-	out, err := cmd.Output()
+	info, err := Probe(path)
 	if err != nil {
 		return 0, err
 	}
-	txt := strings.TrimSpace(string(out))
-	if txt == "" {
-		return 0, fmt.Errorf("ffprobe returned empty")
+	if info.Duration <= 0 {
+		return 0, fmt.Errorf("ffprobe returned no duration")
 	}
-	v, err := strconv.ParseFloat(txt, 64)
-	if err != nil {
-		return 0, err
-	}
-	return v, nil
+	return info.Duration, nil
 }
 
 func parseHMS(s string) (float64, error) {
@@ -278,8 +305,14 @@ func parseHMS(s string) (float64, error) {
 	return h*3600 + m*60 + sec, nil
 }
 
-func listen(ch <-chan tea.Msg) tea.Cmd {
+// listen blocks on a single job's channel and forwards its next message.
+// jobID is unused by the body (messages already carry their own jobID) but
+// keeps call sites self-documenting about which job they're listening to.
+func listen(jobID int, ch <-chan tea.Msg) tea.Cmd {
 	return func() tea.Msg {
+		if ch == nil {
+			return nil
+		}
 		// blocks until a message is available
 		msg, ok := <-ch
 		if !ok {
@@ -290,29 +323,3 @@ func listen(ch <-chan tea.Msg) tea.Cmd {
 	}
 }
 
-func listenForProgress(ch chan tea.Msg) tea.Cmd {
-	return func() tea.Msg {
-		return <-ch // waits for a message
-	}
-}
-
-func readProgress(stdout io.ReadCloser, dur float64, ch chan tea.Msg) {
-	sc := bufio.NewScanner(stdout)
-	for sc.Scan() {
-		line := sc.Text()
-		if strings.HasPrefix(line, "out_time_ms=") {
-			msString := strings.TrimPrefix(line, "out_time_ms=")
-			ms, _ := strconv.ParseFloat(msString, 64)
-			percent := (ms / 1_000.0) / dur
-			if percent < 0 {
-				percent = 0
-			}
-			if percent > 1 {
-				percent = 1
-			}
-
-			ch <- progressMsg(percent)
-		}
-	}
-	ch <- progressMsg(1) // final 100%
-}